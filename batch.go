@@ -0,0 +1,140 @@
+package sample1
+
+import (
+	"context"
+	"time"
+)
+
+// PriceResult is the outcome of looking up a single item as part of a batch request. Err is set
+// if that particular lookup failed; a failure for one item never prevents the others in the same
+// batch from being reported.
+type PriceResult struct {
+	ItemCode string
+	Price    float64
+	Err      error
+}
+
+// BatchOptions tunes how GetPricesForDetailedWithOptions runs a batch of lookups.
+type BatchOptions struct {
+	// FailFast stops launching new lookups as soon as one fails. Lookups not started yet, and
+	// lookups still waiting on their result at that point, are reported as failed with
+	// context.Canceled; as with Context, the upstream calls already in flight are not cancelled.
+	FailFast bool
+	// MaxConcurrency caps how many lookups run at once. 0 means no cap (one per item, as before).
+	MaxConcurrency int
+	// PerItemTimeout, if non-zero, bounds how long a single lookup is waited on before it is
+	// reported as failed with context.DeadlineExceeded. Note that PriceService.GetPriceFor takes
+	// no context, so a timed-out lookup is abandoned rather than actually cancelled: its
+	// goroutine keeps running actualPriceService.GetPriceFor in the background and, if it later
+	// succeeds, still populates the cache for the next caller.
+	PerItemTimeout time.Duration
+	// Context, if set, is honored for cancellation: once it is done, lookups that have not
+	// started yet are reported as failed with its error instead of being started, and lookups
+	// already waiting on their result stop waiting and report ctx.Err(). As with
+	// PerItemTimeout, this does not cancel an upstream call already in flight — PriceService has
+	// no context-aware variant of GetPriceFor — it only stops this batch from waiting on it.
+	Context context.Context
+}
+
+// GetPricesForDetailed gets the prices for several items at once, the same as GetPricesFor, but
+// returns one PriceResult per item in input order instead of aborting on the first error. It is
+// equivalent to GetPricesForDetailedWithOptions with the zero value of BatchOptions.
+func (c *TransparentCache) GetPricesForDetailed(itemCodes ...string) ([]PriceResult, error) {
+	return c.GetPricesForDetailedWithOptions(BatchOptions{}, itemCodes...)
+}
+
+// GetPricesForDetailedWithOptions is GetPricesForDetailed with control over concurrency,
+// cancellation and fail-fast behavior. A semaphore lets at most opts.MaxConcurrency lookups run
+// at once (all of them when it is 0), and results are always returned in the order itemCodes was given,
+// regardless of which lookup finishes first. opts.Context and opts.PerItemTimeout stop a lookup
+// from being waited on, not the underlying actualPriceService call itself; see their doc
+// comments on BatchOptions.
+func (c *TransparentCache) GetPricesForDetailedWithOptions(opts BatchOptions, itemCodes ...string) ([]PriceResult, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cancel := func() {}
+	if opts.FailFast {
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+	}
+
+	maxConcurrency := opts.MaxConcurrency
+	if maxConcurrency <= 0 || maxConcurrency > len(itemCodes) {
+		maxConcurrency = len(itemCodes)
+	}
+
+	results := make([]PriceResult, len(itemCodes))
+	sem := make(chan struct{}, maxConcurrency)
+	done := make(chan struct{}, len(itemCodes))
+
+	for i, itemCode := range itemCodes {
+		select {
+		case <-ctx.Done():
+			results[i] = PriceResult{ItemCode: itemCode, Err: ctx.Err()}
+			done <- struct{}{}
+			continue
+		case sem <- struct{}{}:
+		}
+		go func(i int, itemCode string) {
+			defer func() { <-sem; done <- struct{}{} }()
+			results[i] = c.getPriceForBatchItem(ctx, itemCode, opts.PerItemTimeout)
+			if opts.FailFast && results[i].Err != nil {
+				cancel()
+			}
+		}(i, itemCode)
+	}
+
+	for range itemCodes {
+		<-done
+	}
+
+	var firstErr error
+	for _, r := range results {
+		if r.Err != nil {
+			firstErr = r.Err
+			break
+		}
+	}
+
+	return results, firstErr
+}
+
+func (c *TransparentCache) getPriceForBatchItem(ctx context.Context, itemCode string, perItemTimeout time.Duration) PriceResult {
+	select {
+	case <-ctx.Done():
+		return PriceResult{ItemCode: itemCode, Err: ctx.Err()}
+	default:
+	}
+
+	type lookupResult struct {
+		price float64
+		err   error
+	}
+	ch := make(chan lookupResult, 1)
+	go func() {
+		price, err := c.GetPriceFor(itemCode)
+		ch <- lookupResult{price: price, err: err}
+	}()
+
+	if perItemTimeout > 0 {
+		timer := time.NewTimer(perItemTimeout)
+		defer timer.Stop()
+		select {
+		case r := <-ch:
+			return PriceResult{ItemCode: itemCode, Price: r.price, Err: r.err}
+		case <-timer.C:
+			return PriceResult{ItemCode: itemCode, Err: context.DeadlineExceeded}
+		case <-ctx.Done():
+			return PriceResult{ItemCode: itemCode, Err: ctx.Err()}
+		}
+	}
+
+	select {
+	case r := <-ch:
+		return PriceResult{ItemCode: itemCode, Price: r.price, Err: r.err}
+	case <-ctx.Done():
+		return PriceResult{ItemCode: itemCode, Err: ctx.Err()}
+	}
+}
@@ -0,0 +1,62 @@
+package sample1
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type itemPriceService struct {
+	failItem string
+	delay    time.Duration
+}
+
+func (s *itemPriceService) GetPriceFor(itemCode string) (float64, error) {
+	time.Sleep(s.delay)
+	if itemCode == s.failItem {
+		return 0, errUpstreamDown
+	}
+	return 1, nil
+}
+
+func TestGetPricesForDetailedWithOptionsReportsPartialErrors(t *testing.T) {
+	svc := &itemPriceService{failItem: "MSFT"}
+	cache := NewTransparentCache(svc, time.Minute)
+
+	results, err := cache.GetPricesForDetailedWithOptions(BatchOptions{}, "GOOG", "MSFT", "AAPL")
+	if !errors.Is(err, errUpstreamDown) {
+		t.Fatalf("GetPricesForDetailedWithOptions error = %v, want errUpstreamDown", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	for _, r := range results {
+		wantErr := r.ItemCode == "MSFT"
+		if (r.Err != nil) != wantErr {
+			t.Errorf("result for %q: Err = %v, want error = %v", r.ItemCode, r.Err, wantErr)
+		}
+	}
+}
+
+func TestGetPricesForDetailedWithOptionsFailFastStopsLaunchingLookups(t *testing.T) {
+	svc := &itemPriceService{failItem: "GOOG", delay: 20 * time.Millisecond}
+	cache := NewTransparentCache(svc, time.Minute)
+
+	results, err := cache.GetPricesForDetailedWithOptions(BatchOptions{
+		FailFast:       true,
+		MaxConcurrency: 1,
+	}, "GOOG", "MSFT", "AAPL")
+	if err == nil {
+		t.Fatalf("GetPricesForDetailedWithOptions returned no error, want one")
+	}
+	if results[0].Err == nil {
+		t.Fatalf("result for GOOG (the failing item) has no error")
+	}
+	for _, itemCode := range []string{"MSFT", "AAPL"} {
+		for _, r := range results {
+			if r.ItemCode == itemCode && r.Err == nil {
+				t.Errorf("result for %q succeeded, want it abandoned once FailFast cancelled the batch", itemCode)
+			}
+		}
+	}
+}
@@ -19,7 +19,25 @@ type PriceService interface {
 type TransparentCache struct {
 	actualPriceService PriceService
 	maxAge             time.Duration
-	prices             map[string]priceInformation
+
+	mu       sync.RWMutex
+	prices   map[string]priceInformation
+	inFlight map[string]*inFlightCall
+
+	maxEntries     int
+	evictionPolicy EvictionPolicy
+	onEvict        func(itemCode string, reason EvictReason)
+	lru            *lruIndex
+
+	sweepInterval time.Duration
+	stopSweep     chan struct{}
+	sweepDone     chan struct{}
+
+	fileCache FileCache
+	negCache  *negativeCache
+
+	metrics Metrics
+	logger  Logger
 }
 
 type priceInformation struct {
@@ -32,50 +50,171 @@ type result struct {
 	err   error
 }
 
+// inFlightCall represents an upstream GetPriceFor call that is already running for a given
+// itemCode. Callers that arrive while it is in flight wait on done instead of issuing their
+// own call, and then share its result.
+type inFlightCall struct {
+	done  chan struct{}
+	value float64
+	err   error
+}
+
 func NewTransparentCache(actualPriceService PriceService, maxAge time.Duration) *TransparentCache {
+	return newTransparentCache(actualPriceService, maxAge)
+}
+
+// newTransparentCache builds the zero-value TransparentCache shared by every constructor, with
+// its maps and no-op Metrics/Logger initialized so NewTransparentCacheWithOptions only needs to
+// set the fields its features add.
+func newTransparentCache(actualPriceService PriceService, maxAge time.Duration) *TransparentCache {
 	return &TransparentCache{
 		actualPriceService: actualPriceService,
 		maxAge:             maxAge,
 		prices:             map[string]priceInformation{},
+		inFlight:           map[string]*inFlightCall{},
+		metrics:            noopMetrics{},
+		logger:             noopLogger{},
+	}
+}
+
+// setPriceLocked stores info for itemCode and, if MaxEntries is set and was just exceeded,
+// evicts the least-recently-used entry. The caller must hold c.mu, and is responsible for
+// reporting the returned eviction (if any) to c.metrics/c.onEvict after unlocking. It is the only
+// way prices should be inserted, so every insert site enforces MaxEntries the same way.
+func (c *TransparentCache) setPriceLocked(itemCode string, info priceInformation) (evicted string, evictedOK bool) {
+	c.prices[itemCode] = info
+	if c.lru != nil {
+		c.lru.touch(itemCode)
+		if c.maxEntries > 0 && len(c.prices) > c.maxEntries {
+			evicted, evictedOK = c.lru.evictOldest()
+			if evictedOK {
+				delete(c.prices, evicted)
+			}
+		}
 	}
+	return evicted, evictedOK
 }
 
 // GetPriceFor gets the price for the item, either from the cache or the actual service if it was not cached or too old
+// Concurrent callers asking for the same itemCode while it is not cached are coalesced into a
+// single call to actualPriceService, and all share its result.
 func (c *TransparentCache) GetPriceFor(itemCode string) (float64, error) {
 
+	c.mu.RLock()
 	price, ok := c.prices[itemCode]
+	c.mu.RUnlock()
 	if ok {
 		if (time.Now().Sub(price.lastReading)) <= c.maxAge {
+			if c.lru != nil {
+				c.mu.Lock()
+				c.lru.touch(itemCode)
+				c.mu.Unlock()
+			}
+			c.metrics.Hit(itemCode)
 			return price.value, nil
 		}
 	}
+
+	c.metrics.Miss(itemCode)
+
+	if c.fileCache != nil {
+		if v, lastReading, found, err := c.fileCache.Get(itemCode); err == nil && found {
+			c.mu.Lock()
+			evicted, evictedOK := c.setPriceLocked(itemCode, priceInformation{lastReading: lastReading, value: v})
+			c.mu.Unlock()
+			if evictedOK {
+				c.metrics.Eviction(evicted, EvictReasonCapacity)
+				if c.onEvict != nil {
+					c.onEvict(evicted, EvictReasonCapacity)
+				}
+			}
+			return v, nil
+		}
+	}
+
+	if c.negCache != nil {
+		if err, blocked := c.negCache.check(itemCode); blocked {
+			return 0, err
+		}
+	}
+
+	c.mu.Lock()
+	if call, ok := c.inFlight[itemCode]; ok {
+		c.mu.Unlock()
+		c.metrics.InFlightCoalesced(itemCode)
+		<-call.done
+		return call.value, call.err
+	}
+	call := &inFlightCall{done: make(chan struct{})}
+	c.inFlight[itemCode] = call
+	c.mu.Unlock()
+
+	upstreamStart := time.Now()
 	v, err := c.actualPriceService.GetPriceFor(itemCode)
+	c.metrics.ObserveUpstreamLatency(itemCode, time.Since(upstreamStart))
 	if err != nil {
-		return 0, fmt.Errorf("getting price from service : %v", err.Error())
+		call.err = fmt.Errorf("getting price from service : %w", err)
+		c.metrics.Error(itemCode, err)
+		c.logger.Warnf("price lookup for %q failed: %v", itemCode, err)
+		if c.negCache != nil {
+			c.negCache.recordFailure(itemCode, err)
+		}
+	} else {
+		call.value = v
+		c.logger.Debugf("price lookup for %q succeeded: %v", itemCode, v)
+		if c.negCache != nil {
+			c.negCache.reset(itemCode)
+		}
 	}
 
-	price = priceInformation{
-		lastReading: time.Now(),
-		value:       v,
+	now := time.Now()
+
+	c.mu.Lock()
+	var evicted string
+	var evictedOK bool
+	if call.err == nil {
+		evicted, evictedOK = c.setPriceLocked(itemCode, priceInformation{lastReading: now, value: call.value})
+	}
+	delete(c.inFlight, itemCode)
+	c.mu.Unlock()
+
+	if evictedOK {
+		c.metrics.Eviction(evicted, EvictReasonCapacity)
+		if c.onEvict != nil {
+			c.onEvict(evicted, EvictReasonCapacity)
+		}
 	}
 
-	c.prices[itemCode] = price
-	return price.value, nil
+	if call.err == nil && c.fileCache != nil {
+		_ = c.fileCache.Set(itemCode, call.value, now)
+	}
+
+	close(call.done)
+	return call.value, call.err
 }
 
 // GetPricesFor gets the prices for several items at once, some might be found in the cache, others might not
 // If any of the operations returns an error, it should return an error as well
+// For partial results and per-item errors instead of aborting on the first failure, see
+// GetPricesForDetailed.
 func (c *TransparentCache) GetPricesFor(itemCodes ...string) ([]float64, error) {
 	results := []float64{}
 
 	ch := make(chan result, len(itemCodes))
 	c.getPricesForAsy(ch, itemCodes...)
 
+	var firstErr error
 	for r := range ch {
-		if r.err != nil {
-			return []float64{}, r.err
+		if r.err != nil && firstErr == nil {
+			firstErr = r.err
+			continue
 		}
-		results = append(results, r.price)
+		if firstErr == nil {
+			results = append(results, r.price)
+		}
+	}
+	if firstErr != nil {
+		return []float64{}, firstErr
 	}
 	return results, nil
 }
@@ -0,0 +1,46 @@
+package sample1
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingPriceService struct {
+	calls atomic.Int64
+	delay time.Duration
+	price float64
+}
+
+func (s *countingPriceService) GetPriceFor(itemCode string) (float64, error) {
+	s.calls.Add(1)
+	time.Sleep(s.delay)
+	return s.price, nil
+}
+
+func TestGetPriceForCoalescesConcurrentCalls(t *testing.T) {
+	svc := &countingPriceService{delay: 50 * time.Millisecond, price: 42}
+	cache := NewTransparentCache(svc, time.Minute)
+
+	const callers = 20
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			price, err := cache.GetPriceFor("GOOG")
+			if err != nil {
+				t.Errorf("GetPriceFor returned an error: %v", err)
+			}
+			if price != svc.price {
+				t.Errorf("GetPriceFor returned %v, want %v", price, svc.price)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := svc.calls.Load(); got != 1 {
+		t.Errorf("actualPriceService was called %d times, want 1 (concurrent callers should be coalesced)", got)
+	}
+}
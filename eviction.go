@@ -0,0 +1,121 @@
+package sample1
+
+import (
+	"container/list"
+	"time"
+)
+
+// EvictionPolicy selects the strategy TransparentCache uses to pick a victim once MaxEntries is
+// reached. Only EvictionPolicyLRU is implemented today; the type exists so LFU/FIFO can be added
+// later without changing the Options shape.
+type EvictionPolicy int
+
+const (
+	// EvictionPolicyLRU evicts the least-recently-used entry first.
+	EvictionPolicyLRU EvictionPolicy = iota
+)
+
+// EvictReason tells an OnEvict callback why an entry was removed.
+type EvictReason int
+
+const (
+	// EvictReasonCapacity means the entry was evicted to make room under MaxEntries.
+	EvictReasonCapacity EvictReason = iota
+	// EvictReasonExpired means the entry was removed by the background sweeper because it was
+	// older than MaxAge.
+	EvictReasonExpired
+)
+
+// Close stops the background sweeper started by NewTransparentCacheWithOptions, if any. It is
+// safe to call on a cache that has no sweeper.
+func (c *TransparentCache) Close() {
+	if c.stopSweep == nil {
+		return
+	}
+	close(c.stopSweep)
+	<-c.sweepDone
+}
+
+func (c *TransparentCache) sweepLoop() {
+	defer close(c.sweepDone)
+
+	ticker := time.NewTicker(c.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopSweep:
+			return
+		case <-ticker.C:
+			c.sweepExpired()
+		}
+	}
+}
+
+func (c *TransparentCache) sweepExpired() {
+	now := time.Now()
+
+	c.mu.Lock()
+	var expired []string
+	for itemCode, price := range c.prices {
+		if now.Sub(price.lastReading) > c.maxAge {
+			expired = append(expired, itemCode)
+		}
+	}
+	for _, itemCode := range expired {
+		delete(c.prices, itemCode)
+		if c.lru != nil {
+			c.lru.remove(itemCode)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, itemCode := range expired {
+		c.metrics.Eviction(itemCode, EvictReasonExpired)
+		if c.onEvict != nil {
+			c.onEvict(itemCode, EvictReasonExpired)
+		}
+	}
+}
+
+// lruIndex tracks recency of use for the LRU eviction policy via a doubly-linked list. All of
+// its methods expect the caller to already hold TransparentCache.mu.
+type lruIndex struct {
+	order *list.List
+	elems map[string]*list.Element
+}
+
+func newLRUIndex() *lruIndex {
+	return &lruIndex{
+		order: list.New(),
+		elems: map[string]*list.Element{},
+	}
+}
+
+// touch marks itemCode as the most recently used entry, inserting it if it wasn't tracked yet.
+func (l *lruIndex) touch(itemCode string) {
+	if elem, ok := l.elems[itemCode]; ok {
+		l.order.MoveToFront(elem)
+		return
+	}
+	l.elems[itemCode] = l.order.PushFront(itemCode)
+}
+
+func (l *lruIndex) remove(itemCode string) {
+	if elem, ok := l.elems[itemCode]; ok {
+		l.order.Remove(elem)
+		delete(l.elems, itemCode)
+	}
+}
+
+// evictOldest removes and returns the least-recently-used itemCode, if any.
+func (l *lruIndex) evictOldest() (string, bool) {
+	elem := l.order.Back()
+	if elem == nil {
+		return "", false
+	}
+	itemCode := elem.Value.(string)
+	l.order.Remove(elem)
+	delete(l.elems, itemCode)
+	return itemCode, true
+}
@@ -0,0 +1,49 @@
+package sample1
+
+import (
+	"testing"
+	"time"
+)
+
+type staticPriceService struct {
+	price float64
+}
+
+func (s *staticPriceService) GetPriceFor(itemCode string) (float64, error) {
+	return s.price, nil
+}
+
+func TestSweeperExpiresStaleEntries(t *testing.T) {
+	svc := &staticPriceService{price: 42}
+	cache, err := NewTransparentCacheWithOptions(svc, Options{
+		MaxAge:        10 * time.Millisecond,
+		SweepInterval: 5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewTransparentCacheWithOptions: %v", err)
+	}
+	defer cache.Close()
+
+	if _, err := cache.GetPriceFor("GOOG"); err != nil {
+		t.Fatalf("GetPriceFor: %v", err)
+	}
+
+	cache.mu.RLock()
+	_, ok := cache.prices["GOOG"]
+	cache.mu.RUnlock()
+	if !ok {
+		t.Fatalf("GOOG should be cached right after the lookup")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		cache.mu.RLock()
+		_, ok := cache.prices["GOOG"]
+		cache.mu.RUnlock()
+		if !ok {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("sweeper did not remove the expired GOOG entry within the deadline")
+}
@@ -0,0 +1,166 @@
+package sample1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FileCache is the second cache tier consulted by TransparentCache when an itemCode is not
+// found (or is stale) in memory. Implementations are expected to be safe for concurrent use.
+type FileCache interface {
+	// Get returns the last known value and reading time for itemCode. found is false if there
+	// is no entry, or if the entry on disk is older than the cache's configured MaxAge.
+	Get(itemCode string) (value float64, lastReading time.Time, found bool, err error)
+	// Set persists value as the latest reading for itemCode.
+	Set(itemCode string, value float64, lastReading time.Time) error
+}
+
+// FileCacheConfig configures a disk-backed FileCache.
+type FileCacheConfig struct {
+	// Dir is the directory entries are written to. It is created if it does not exist.
+	Dir string
+	// MaxAge is how long an on-disk entry is honored before it is treated as a miss.
+	MaxAge time.Duration
+	// MaxSizeBytes caps the total size of Dir. Once exceeded, the oldest entries (by write time)
+	// are removed until the cache fits again. 0 means unbounded.
+	MaxSizeBytes int64
+}
+
+// fileCacheEntry is the JSON header written alongside each cached price.
+type fileCacheEntry struct {
+	Value       float64   `json:"value"`
+	LastReading time.Time `json:"lastReading"`
+}
+
+type diskFileCache struct {
+	cfg FileCacheConfig
+}
+
+func newDiskFileCache(cfg FileCacheConfig) (*diskFileCache, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &diskFileCache{cfg: cfg}, nil
+}
+
+func (f *diskFileCache) pathFor(itemCode string) string {
+	sum := sha256.Sum256([]byte(itemCode))
+	return filepath.Join(f.cfg.Dir, hex.EncodeToString(sum[:]))
+}
+
+func (f *diskFileCache) Get(itemCode string) (float64, time.Time, bool, error) {
+	path := f.pathFor(itemCode)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, time.Time{}, false, nil
+		}
+		return 0, time.Time{}, false, err
+	}
+	if time.Since(info.ModTime()) > f.cfg.MaxAge {
+		// Remove the stale entry instead of just ignoring it, so entries for items that stop
+		// being requested don't accumulate on disk forever.
+		_ = os.Remove(path)
+		return 0, time.Time{}, false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
+	var entry fileCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return 0, time.Time{}, false, err
+	}
+
+	return entry.Value, entry.LastReading, true, nil
+}
+
+func (f *diskFileCache) Set(itemCode string, value float64, lastReading time.Time) error {
+	data, err := json.Marshal(fileCacheEntry{Value: value, LastReading: lastReading})
+	if err != nil {
+		return err
+	}
+
+	path := f.pathFor(itemCode)
+	tmp, err := os.CreateTemp(f.cfg.Dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	if f.cfg.MaxSizeBytes > 0 {
+		return f.enforceMaxSize()
+	}
+	return nil
+}
+
+// enforceMaxSize is the janitor: it removes the oldest entries, by write time, until Dir fits
+// within MaxSizeBytes. It intentionally uses ModTime rather than access time: access time is
+// exposed by os.FileInfo in a platform-specific way (e.g. syscall.Stat_t's atime field is laid
+// out differently per GOOS), and a library has no business assuming the layout of whichever OS
+// happens to run the build.
+func (f *diskFileCache) enforceMaxSize() error {
+	entries, err := os.ReadDir(f.cfg.Dir)
+	if err != nil {
+		return err
+	}
+
+	type fileStat struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []fileStat
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(f.cfg.Dir, e.Name())
+		files = append(files, fileStat{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	if total <= f.cfg.MaxSizeBytes {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	for _, fi := range files {
+		if total <= f.cfg.MaxSizeBytes {
+			break
+		}
+		if err := os.Remove(fi.path); err != nil {
+			continue
+		}
+		total -= fi.size
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package sample1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiskFileCacheRoundTrip(t *testing.T) {
+	fc, err := newDiskFileCache(FileCacheConfig{
+		Dir:    t.TempDir(),
+		MaxAge: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("newDiskFileCache: %v", err)
+	}
+
+	if _, _, found, err := fc.Get("GOOG"); err != nil || found {
+		t.Fatalf("Get on empty cache: found=%v err=%v, want found=false err=nil", found, err)
+	}
+
+	now := time.Now()
+	if err := fc.Set("GOOG", 42, now); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	value, lastReading, found, err := fc.Get("GOOG")
+	if err != nil || !found {
+		t.Fatalf("Get after Set: found=%v err=%v, want found=true err=nil", found, err)
+	}
+	if value != 42 {
+		t.Errorf("Get returned value %v, want 42", value)
+	}
+	if !lastReading.Equal(now) {
+		t.Errorf("Get returned lastReading %v, want %v", lastReading, now)
+	}
+}
+
+func TestDiskFileCacheExpiresStaleEntries(t *testing.T) {
+	fc, err := newDiskFileCache(FileCacheConfig{
+		Dir:    t.TempDir(),
+		MaxAge: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("newDiskFileCache: %v", err)
+	}
+
+	if err := fc.Set("GOOG", 42, time.Now()); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if _, _, found, err := fc.Get("GOOG"); err != nil || found {
+		t.Fatalf("Get of stale entry: found=%v err=%v, want found=false err=nil", found, err)
+	}
+}
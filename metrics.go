@@ -0,0 +1,127 @@
+package sample1
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Metrics receives notifications of cache decisions so operators can see hit ratio, coalescing
+// effectiveness and eviction pressure in production. All methods must be safe for concurrent use.
+type Metrics interface {
+	Hit(itemCode string)
+	Miss(itemCode string)
+	InFlightCoalesced(itemCode string)
+	Eviction(itemCode string, reason EvictReason)
+	Error(itemCode string, err error)
+	ObserveUpstreamLatency(itemCode string, d time.Duration)
+}
+
+// noopMetrics is the default Metrics implementation: it does nothing, so TransparentCache never
+// has to nil-check c.metrics.
+type noopMetrics struct{}
+
+func (noopMetrics) Hit(itemCode string)                                     {}
+func (noopMetrics) Miss(itemCode string)                                    {}
+func (noopMetrics) InFlightCoalesced(itemCode string)                       {}
+func (noopMetrics) Eviction(itemCode string, reason EvictReason)            {}
+func (noopMetrics) Error(itemCode string, err error)                        {}
+func (noopMetrics) ObserveUpstreamLatency(itemCode string, d time.Duration) {}
+
+// Logger is the subset of a structured logger TransparentCache needs. *slog.Logger satisfies it
+// through SlogLogger.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// noopLogger is the default Logger: silent, so c.logger is never nil.
+type noopLogger struct{}
+
+func (noopLogger) Debugf(format string, args ...interface{}) {}
+func (noopLogger) Warnf(format string, args ...interface{})  {}
+func (noopLogger) Errorf(format string, args ...interface{}) {}
+
+// SlogLogger adapts a *slog.Logger to Logger.
+type SlogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger builds a Logger backed by l.
+func NewSlogLogger(l *slog.Logger) *SlogLogger {
+	return &SlogLogger{l: l}
+}
+
+func (s *SlogLogger) Debugf(format string, args ...interface{}) {
+	s.l.Debug(fmt.Sprintf(format, args...))
+}
+
+func (s *SlogLogger) Warnf(format string, args ...interface{}) {
+	s.l.Warn(fmt.Sprintf(format, args...))
+}
+
+func (s *SlogLogger) Errorf(format string, args ...interface{}) {
+	s.l.Error(fmt.Sprintf(format, args...))
+}
+
+// Counter is the subset of prometheus.Counter that PromMetrics needs: the value returned by
+// prometheus.NewCounter, or a single prometheus.CounterVec.WithLabelValues(...) result, both
+// satisfy it as-is.
+type Counter interface {
+	Inc()
+}
+
+// Histogram is the subset of prometheus.Histogram that PromMetrics needs. The value returned by
+// prometheus.NewHistogram satisfies it as-is.
+type Histogram interface {
+	Observe(v float64)
+}
+
+// PromMetrics is a Metrics implementation backed by Prometheus-style client metrics. It depends
+// only on the small Counter/Histogram interfaces above, not on the prometheus module itself, so
+// this package has no hard dependency on it: construct the real
+// prometheus.Counter/CounterVec/Histogram values, register them on your prometheus.Registerer the
+// usual way, and pass them to NewPromMetrics. Use WithLabelValues against your own
+// prometheus.CounterVec to produce the two eviction counters.
+type PromMetrics struct {
+	hits              Counter
+	misses            Counter
+	inFlightCoalesced Counter
+	evictionsCapacity Counter
+	evictionsExpired  Counter
+	errors            Counter
+	upstreamLatency   Histogram
+}
+
+// NewPromMetrics builds a Metrics implementation from already-registered Prometheus metrics.
+// Pass the result to NewTransparentCacheWithOptions via Options.Metrics.
+func NewPromMetrics(hits, misses, inFlightCoalesced, evictionsCapacity, evictionsExpired, errors Counter, upstreamLatency Histogram) *PromMetrics {
+	return &PromMetrics{
+		hits:              hits,
+		misses:            misses,
+		inFlightCoalesced: inFlightCoalesced,
+		evictionsCapacity: evictionsCapacity,
+		evictionsExpired:  evictionsExpired,
+		errors:            errors,
+		upstreamLatency:   upstreamLatency,
+	}
+}
+
+func (m *PromMetrics) Hit(itemCode string)               { m.hits.Inc() }
+func (m *PromMetrics) Miss(itemCode string)              { m.misses.Inc() }
+func (m *PromMetrics) InFlightCoalesced(itemCode string) { m.inFlightCoalesced.Inc() }
+
+func (m *PromMetrics) Eviction(itemCode string, reason EvictReason) {
+	if reason == EvictReasonExpired {
+		m.evictionsExpired.Inc()
+		return
+	}
+	m.evictionsCapacity.Inc()
+}
+
+func (m *PromMetrics) Error(itemCode string, err error) { m.errors.Inc() }
+
+func (m *PromMetrics) ObserveUpstreamLatency(itemCode string, d time.Duration) {
+	m.upstreamLatency.Observe(d.Seconds())
+}
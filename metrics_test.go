@@ -0,0 +1,28 @@
+package sample1
+
+import "testing"
+
+type fakeCounter struct{ count int }
+
+func (c *fakeCounter) Inc() { c.count++ }
+
+type fakeHistogram struct{ observations []float64 }
+
+func (h *fakeHistogram) Observe(v float64) { h.observations = append(h.observations, v) }
+
+func TestPromMetricsRoutesEvictionsByReason(t *testing.T) {
+	capacity := &fakeCounter{}
+	expired := &fakeCounter{}
+	m := NewPromMetrics(&fakeCounter{}, &fakeCounter{}, &fakeCounter{}, capacity, expired, &fakeCounter{}, &fakeHistogram{})
+
+	m.Eviction("GOOG", EvictReasonCapacity)
+	m.Eviction("MSFT", EvictReasonExpired)
+	m.Eviction("AAPL", EvictReasonExpired)
+
+	if capacity.count != 1 {
+		t.Errorf("evictionsCapacity.Inc() called %d times, want 1", capacity.count)
+	}
+	if expired.count != 2 {
+		t.Errorf("evictionsExpired.Inc() called %d times, want 2", expired.count)
+	}
+}
@@ -0,0 +1,111 @@
+package sample1
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// NegativeCacheOptions configures how TransparentCache remembers failures from
+// actualPriceService so a broken upstream isn't retried on every single request.
+type NegativeCacheOptions struct {
+	// InitialBackoff is how long the first failure for an itemCode is cached for.
+	InitialBackoff time.Duration
+	// MaxBackoff caps how long a run of consecutive failures can push the backoff to.
+	MaxBackoff time.Duration
+	// MaxFailures caps how many consecutive failures are tracked before the backoff stops
+	// growing; 0 means unbounded.
+	MaxFailures int
+	// CacheErrorPredicate decides whether an error from actualPriceService is worth caching.
+	// If nil, every error is cached.
+	CacheErrorPredicate func(error) bool
+}
+
+// failureInfo is the negative-cache entry for a single itemCode.
+type failureInfo struct {
+	err         error
+	nextAttempt time.Time
+	consecutive int
+}
+
+// cachedFailureError is returned by GetPriceFor when an itemCode is currently within its
+// negative-cache backoff window. It wraps the original upstream error so errors.Is/As still see
+// through to it.
+type cachedFailureError struct {
+	err error
+}
+
+func (e *cachedFailureError) Error() string {
+	return fmt.Sprintf("getting price from service : %v (cached failure, backing off)", e.err)
+}
+
+func (e *cachedFailureError) Unwrap() error {
+	return e.err
+}
+
+// negativeCache tracks recent actualPriceService failures per itemCode, guarded by the same
+// mutex as TransparentCache.prices.
+type negativeCache struct {
+	mu       *sync.RWMutex
+	opts     NegativeCacheOptions
+	failures map[string]*failureInfo
+}
+
+func newNegativeCache(mu *sync.RWMutex, opts NegativeCacheOptions) *negativeCache {
+	return &negativeCache{
+		mu:       mu,
+		opts:     opts,
+		failures: map[string]*failureInfo{},
+	}
+}
+
+// check returns the cached failure and true if itemCode is still within its backoff window.
+func (n *negativeCache) check(itemCode string) (error, bool) {
+	n.mu.RLock()
+	fail, ok := n.failures[itemCode]
+	n.mu.RUnlock()
+	if !ok || !time.Now().Before(fail.nextAttempt) {
+		return nil, false
+	}
+	return &cachedFailureError{err: fail.err}, true
+}
+
+// recordFailure registers a failed upstream call for itemCode, doubling its backoff from the
+// previous consecutive failure.
+func (n *negativeCache) recordFailure(itemCode string, err error) {
+	if n.opts.CacheErrorPredicate != nil && !n.opts.CacheErrorPredicate(err) {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	fail, ok := n.failures[itemCode]
+	if !ok {
+		fail = &failureInfo{}
+		n.failures[itemCode] = fail
+	}
+
+	if n.opts.MaxFailures <= 0 || fail.consecutive < n.opts.MaxFailures {
+		fail.consecutive++
+	}
+
+	backoff := n.opts.InitialBackoff
+	for i := 1; i < fail.consecutive; i++ {
+		backoff *= 2
+		if n.opts.MaxBackoff > 0 && backoff >= n.opts.MaxBackoff {
+			backoff = n.opts.MaxBackoff
+			break
+		}
+	}
+
+	fail.err = err
+	fail.nextAttempt = time.Now().Add(backoff)
+}
+
+// reset clears any tracked failures for itemCode after a successful call.
+func (n *negativeCache) reset(itemCode string) {
+	n.mu.Lock()
+	delete(n.failures, itemCode)
+	n.mu.Unlock()
+}
@@ -0,0 +1,46 @@
+package sample1
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errUpstreamDown = errors.New("upstream down")
+
+type failingPriceService struct {
+	calls int
+}
+
+func (s *failingPriceService) GetPriceFor(itemCode string) (float64, error) {
+	s.calls++
+	return 0, errUpstreamDown
+}
+
+func TestNegativeCacheBacksOffAndPreservesErrorsIs(t *testing.T) {
+	svc := &failingPriceService{}
+	cache, err := NewTransparentCacheWithOptions(svc, Options{
+		MaxAge: time.Minute,
+		NegativeCache: &NegativeCacheOptions{
+			InitialBackoff: time.Hour,
+			MaxBackoff:     time.Hour,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewTransparentCacheWithOptions: %v", err)
+	}
+
+	_, liveErr := cache.GetPriceFor("GOOG")
+	if !errors.Is(liveErr, errUpstreamDown) {
+		t.Errorf("live failure: errors.Is(err, errUpstreamDown) = false, want true (got %v)", liveErr)
+	}
+
+	_, cachedErr := cache.GetPriceFor("GOOG")
+	if !errors.Is(cachedErr, errUpstreamDown) {
+		t.Errorf("cached failure: errors.Is(err, errUpstreamDown) = false, want true (got %v)", cachedErr)
+	}
+
+	if svc.calls != 1 {
+		t.Errorf("actualPriceService was called %d times, want 1 (second call should be served from the negative cache)", svc.calls)
+	}
+}
@@ -0,0 +1,86 @@
+package sample1
+
+import (
+	"fmt"
+	"time"
+)
+
+// Options configures every optional feature TransparentCache supports, so that capacity
+// eviction, on-disk persistence, negative caching and observability can all be combined on the
+// same cache instance. Zero-valued fields simply leave the corresponding feature disabled.
+type Options struct {
+	// MaxAge is how long a cached price is considered fresh, same as the maxAge argument to
+	// NewTransparentCache.
+	MaxAge time.Duration
+
+	// MaxEntries caps how many prices are kept in memory. 0 means unlimited.
+	MaxEntries int
+	// EvictionPolicy picks the victim once MaxEntries is reached. Only EvictionPolicyLRU (the
+	// zero value) is implemented; any other value makes NewTransparentCacheWithOptions return an
+	// error.
+	EvictionPolicy EvictionPolicy
+	// SweepInterval, if non-zero, starts a background goroutine that periodically removes
+	// entries older than MaxAge. Stop it with Close.
+	SweepInterval time.Duration
+	// OnEvict, if set, is called after an entry is removed, either for capacity or expiry.
+	OnEvict func(itemCode string, reason EvictReason)
+
+	// FileCache, if set, adds an on-disk cache tier so prices survive process restarts.
+	FileCache *FileCacheConfig
+
+	// NegativeCache, if set, makes upstream failures back off instead of being retried on every
+	// request for the same itemCode.
+	NegativeCache *NegativeCacheOptions
+
+	// Metrics, if set, receives cache hit/miss/eviction/error notifications. Defaults to a
+	// no-op implementation.
+	Metrics Metrics
+	// Logger, if set, receives debug/warn/error notifications about cache decisions. Defaults
+	// to a no-op implementation.
+	Logger Logger
+}
+
+// NewTransparentCacheWithOptions builds a TransparentCache with any combination of capacity
+// eviction, a background TTL sweeper, an on-disk cache tier, negative caching and observability
+// hooks enabled, according to opts. Every optional feature is configured through this single
+// constructor instead of having one of its own, so they can all be used together.
+func NewTransparentCacheWithOptions(actualPriceService PriceService, opts Options) (*TransparentCache, error) {
+	if opts.EvictionPolicy != EvictionPolicyLRU {
+		return nil, fmt.Errorf("unsupported eviction policy: %v", opts.EvictionPolicy)
+	}
+
+	c := newTransparentCache(actualPriceService, opts.MaxAge)
+
+	c.maxEntries = opts.MaxEntries
+	c.evictionPolicy = opts.EvictionPolicy
+	c.onEvict = opts.OnEvict
+	c.lru = newLRUIndex()
+
+	if opts.SweepInterval > 0 {
+		c.sweepInterval = opts.SweepInterval
+		c.stopSweep = make(chan struct{})
+		c.sweepDone = make(chan struct{})
+		go c.sweepLoop()
+	}
+
+	if opts.FileCache != nil {
+		fc, err := newDiskFileCache(*opts.FileCache)
+		if err != nil {
+			return nil, fmt.Errorf("creating file cache: %v", err)
+		}
+		c.fileCache = fc
+	}
+
+	if opts.NegativeCache != nil {
+		c.negCache = newNegativeCache(&c.mu, *opts.NegativeCache)
+	}
+
+	if opts.Metrics != nil {
+		c.metrics = opts.Metrics
+	}
+	if opts.Logger != nil {
+		c.logger = opts.Logger
+	}
+
+	return c, nil
+}